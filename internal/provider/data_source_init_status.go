@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	resInitStatus  = provider + "_init_status"
+	argInitialized = "initialized"
+)
+
+func providerDatasourceInitStatus() *schema.Resource {
+	return &schema.Resource{
+		Description: "Data source for checking whether Vault has been initialized, via sys/init-status.",
+
+		ReadContext: dataSourceInitStatusRead,
+
+		Schema: map[string]*schema.Schema{
+			argInitialized: {
+				Description: "Whether Vault is initialized.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceInitStatusRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient)
+
+	initialized, err := client.client.Sys().InitStatus()
+	if err != nil {
+		logError("failed to check init status: %v", err)
+		return diag.FromErr(err)
+	}
+
+	d.SetId(client.client.Address())
+	d.Set(argInitialized, initialized)
+
+	return diag.Diagnostics{}
+}
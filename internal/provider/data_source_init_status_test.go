@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+var testAccDataSourceInitStatusVar = fmt.Sprintf("data.%[1]s.test", resInitStatus)
+var testAccDataSourceInitStatus = fmt.Sprintf(`
+provider "%[1]s" {
+}
+
+data "%[2]s" "test" {
+}
+`, provider, resInitStatus)
+
+func TestAccDataSourceInitStatus(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceInitStatus,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(testAccDataSourceInitStatusVar, argInitialized),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"strings"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// kubeConn holds everything the provider needs to reach a Kubernetes API
+// server and, from there, the Vault instance running behind it.
+type kubeConn struct {
+	configPath  string
+	nameSpace   string
+	serviceName string
+	localPort   string
+	remotePort  string
+	kubeConfig  *rest.Config
+	kubeClient  *kubernetes.Clientset
+
+	// stopCh, when non-nil, stops the background port-forward goroutine
+	// started by startPortForward. Closed when the provider context is
+	// cancelled.
+	stopCh chan struct{}
+}
+
+// buildKubeConn resolves a *rest.Config from a kube_config block. Inline
+// credentials (host/token/certs/etc.) take precedence over the kubeconfig
+// file path, matching the behavior of the terraform-provider-kubernetes
+// backend.
+func buildKubeConn(kc map[string]interface{}) (*kubeConn, error) {
+	c := &kubeConn{}
+
+	restConfig, err := buildRestConfig(kc)
+	if err != nil {
+		return nil, err
+	}
+	c.kubeConfig = restConfig
+
+	kubeClient, err := kubernetes.NewForConfig(c.kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	c.kubeClient = kubeClient
+
+	return c, nil
+}
+
+// buildRestConfig assembles a *rest.Config in order of precedence: the
+// Pod's mounted service account (in_cluster), inline credential fields of
+// a kube_config block, and finally a kubeconfig file on disk.
+func buildRestConfig(kc map[string]interface{}) (*rest.Config, error) {
+	if kc[argKubeInCluster].(bool) {
+		return rest.InClusterConfig()
+	}
+
+	if hasInlineKubeCreds(kc) {
+		return restConfigFromInline(kc)
+	}
+
+	path := kc[argKubeConfigPath].(string)
+	if strings.Contains(path, "~") {
+		homeDir, err := homeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = strings.Replace(path, "~", homeDir, -1)
+	}
+
+	return clientcmd.BuildConfigFromFlags("", path)
+}
+
+// hasInlineKubeCreds reports whether the kube_config block carries enough
+// inline connection info (at minimum a host) to skip reading a kubeconfig
+// file from disk entirely.
+func hasInlineKubeCreds(kc map[string]interface{}) bool {
+	return kc[argKubeHost].(string) != ""
+}
+
+// restConfigFromInline assembles a clientcmdapi.Config in memory from the
+// inline kube_config fields and resolves it into a *rest.Config, the same
+// approach taken by HashiCorp's own Kubernetes remote-state backend.
+func restConfigFromInline(kc map[string]interface{}) (*rest.Config, error) {
+	cluster := clientcmdapi.NewCluster()
+	cluster.Server = kc[argKubeHost].(string)
+	cluster.InsecureSkipTLSVerify = kc[argKubeInsecure].(bool)
+	if ca := kc[argKubeClusterCACert].(string); ca != "" {
+		cluster.CertificateAuthorityData = []byte(ca)
+	}
+
+	authInfo := clientcmdapi.NewAuthInfo()
+	authInfo.Username = kc[argKubeUsername].(string)
+	authInfo.Password = kc[argKubePassword].(string)
+	authInfo.Token = kc[argKubeToken].(string)
+	if cert := kc[argKubeClientCert].(string); cert != "" {
+		authInfo.ClientCertificateData = []byte(cert)
+	}
+	if key := kc[argKubeClientKey].(string); key != "" {
+		authInfo.ClientKeyData = []byte(key)
+	}
+
+	context := clientcmdapi.NewContext()
+	context.Cluster = "default"
+	context.AuthInfo = "default"
+	if v := kc[argKubeConfigContextCluster].(string); v != "" {
+		context.Cluster = v
+	}
+	if v := kc[argKubeConfigContextAuthInfo].(string); v != "" {
+		context.AuthInfo = v
+	}
+
+	apiConfig := clientcmdapi.NewConfig()
+	apiConfig.Clusters[context.Cluster] = cluster
+	apiConfig.AuthInfos[context.AuthInfo] = authInfo
+	apiConfig.Contexts["default"] = context
+	apiConfig.CurrentContext = "default"
+	if v := kc[argKubeConfigContext].(string); v != "" {
+		apiConfig.CurrentContext = v
+		apiConfig.Contexts[v] = context
+	}
+
+	overrides := &clientcmd.ConfigOverrides{}
+	loader := clientcmd.NewDefaultClientConfigLoadingRules()
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientConfigLoader{apiConfig: apiConfig, fallback: loader},
+		overrides,
+	).ClientConfig()
+}
+
+// clientConfigLoader implements clientcmd.ClientConfigLoader by returning an
+// in-memory clientcmdapi.Config instead of reading one from disk, so that
+// clientcmd.NewNonInteractiveDeferredLoadingClientConfig can resolve inline
+// kube_config credentials the same way it resolves a file on disk.
+type clientConfigLoader struct {
+	apiConfig *clientcmdapi.Config
+	fallback  *clientcmd.ClientConfigLoadingRules
+}
+
+func (l *clientConfigLoader) Load() (*clientcmdapi.Config, error) {
+	return l.apiConfig, nil
+}
+
+func (l *clientConfigLoader) GetLoadingPrecedence() []string {
+	return l.fallback.GetLoadingPrecedence()
+}
+
+func (l *clientConfigLoader) GetStartingConfig() (*clientcmdapi.Config, error) {
+	return l.apiConfig, nil
+}
+
+func (l *clientConfigLoader) GetDefaultFilename() string {
+	return l.fallback.GetDefaultFilename()
+}
+
+func (l *clientConfigLoader) IsExplicitFile() bool {
+	return false
+}
+
+func (l *clientConfigLoader) GetExplicitFile() string {
+	return ""
+}
+
+func (l *clientConfigLoader) IsDefaultConfig(config *rest.Config) bool {
+	return l.fallback.IsDefaultConfig(config)
+}
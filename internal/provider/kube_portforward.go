@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+const (
+	portForwardReadyTimeout  = 30 * time.Second
+	portForwardRetryInterval = 2 * time.Second
+)
+
+// startPortForward resolves the kube_config's `service` to a ready Pod and
+// establishes a SPDY port-forward from localPort to remotePort, running the
+// forwarder in a background goroutine for the lifetime of the provider. It
+// blocks until the forwarder reports ready (or times out), since vault_init
+// is typically run immediately after `helm install`, before the Vault Pod
+// has necessarily settled.
+func startPortForward(ctx context.Context, conn *kubeConn) error {
+	pod, err := waitForReadyPod(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	restClient := conn.kubeClient.CoreV1().RESTClient()
+	req := restClient.Post().
+		Resource("pods").
+		Namespace(conn.nameSpace).
+		Name(pod).
+		SubResource("portforward")
+
+	transport, upgrader, err := spdy.RoundTripperFor(conn.kubeConfig)
+	if err != nil {
+		return fmt.Errorf("failed to build SPDY round tripper: %w", err)
+	}
+
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	stopCh := make(chan struct{}, 1)
+	readyCh := make(chan struct{})
+
+	fw, err := portforward.New(
+		dialer,
+		[]string{fmt.Sprintf("%s:%s", conn.localPort, conn.remotePort)},
+		stopCh,
+		readyCh,
+		nil,
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set up port-forward to %s/%s: %w", conn.nameSpace, pod, err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fw.ForwardPorts()
+	}()
+
+	select {
+	case <-readyCh:
+		conn.stopCh = stopCh
+		return nil
+	case err := <-errCh:
+		return fmt.Errorf("port-forward to %s/%s exited before becoming ready: %w", conn.nameSpace, pod, err)
+	case <-time.After(portForwardReadyTimeout):
+		close(stopCh)
+		return fmt.Errorf("timed out waiting for port-forward to %s/%s to become ready", conn.nameSpace, pod)
+	}
+}
+
+// waitForReadyPod resolves the named Service to one of its ready backing
+// Pods, retrying with a fixed backoff since a Service freshly created by
+// `helm install` may not have a ready endpoint yet.
+func waitForReadyPod(ctx context.Context, conn *kubeConn) (string, error) {
+	deadline := time.Now().Add(portForwardReadyTimeout)
+
+	var lastErr error
+	for {
+		pod, err := readyPodForService(ctx, conn)
+		if err == nil {
+			return pod, nil
+		}
+		lastErr = err
+
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("no ready pod found for service %s/%s: %w", conn.nameSpace, conn.serviceName, lastErr)
+		}
+
+		time.Sleep(portForwardRetryInterval)
+	}
+}
+
+// readyPodForService picks a ready Pod matching the Service's selector.
+func readyPodForService(ctx context.Context, conn *kubeConn) (string, error) {
+	svc, err := conn.kubeClient.CoreV1().Services(conn.nameSpace).Get(ctx, conn.serviceName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	if len(svc.Spec.Selector) == 0 {
+		return "", fmt.Errorf("service %s/%s has no selector", conn.nameSpace, conn.serviceName)
+	}
+
+	selector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: svc.Spec.Selector})
+	pods, err := conn.kubeClient.CoreV1().Pods(conn.nameSpace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods.Items {
+		if isPodReady(&pod) {
+			return pod.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("service %s/%s has no ready pods", conn.nameSpace, conn.serviceName)
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
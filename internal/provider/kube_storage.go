@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// secretDataKey is the key under which the serialized api.InitResponse is
+// stored in the Kubernetes Secret created by the `storage.kubernetes`
+// block.
+const secretDataKey = "init.json"
+
+// kubeSecretStorage describes a `storage { kubernetes { ... } }` block.
+type kubeSecretStorage struct {
+	secretName  string
+	namespace   string
+	labels      map[string]string
+	annotations map[string]string
+}
+
+// id returns the k8s:// scheme resource ID used in place of the Vault
+// address when storage.kubernetes is configured.
+func (s kubeSecretStorage) id() string {
+	return fmt.Sprintf("k8s://%s/%s", s.namespace, s.secretName)
+}
+
+// kubeStorageFromResourceData extracts the storage.kubernetes block, if
+// any, from resourceInit's ResourceData.
+func kubeStorageFromResourceData(d *schema.ResourceData) (kubeSecretStorage, bool) {
+	storage, ok := d.GetOk(argStorage)
+	if !ok {
+		return kubeSecretStorage{}, false
+	}
+
+	storageBlocks := storage.([]interface{})
+	if len(storageBlocks) == 0 || storageBlocks[0] == nil {
+		return kubeSecretStorage{}, false
+	}
+
+	kubeBlocks := storageBlocks[0].(map[string]interface{})[argStorageKubernetes].([]interface{})
+	if len(kubeBlocks) == 0 || kubeBlocks[0] == nil {
+		return kubeSecretStorage{}, false
+	}
+
+	kube := kubeBlocks[0].(map[string]interface{})
+
+	return kubeSecretStorage{
+		secretName:  kube[argStorageSecretName].(string),
+		namespace:   kube[argStorageNamespace].(string),
+		labels:      toStringMap(kube[argStorageLabels].(map[string]interface{})),
+		annotations: toStringMap(kube[argStorageAnnotations].(map[string]interface{})),
+	}, true
+}
+
+func toStringMap(m map[string]interface{}) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v.(string)
+	}
+	return out
+}
+
+// createInitSecret serializes res as JSON and creates an Opaque Secret
+// holding it.
+func createInitSecret(ctx context.Context, kubeClient *kubernetes.Clientset, storage kubeSecretStorage, res *api.InitResponse) error {
+	data, err := json.Marshal(res)
+	if err != nil {
+		return fmt.Errorf("failed to marshal init response: %w", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        storage.secretName,
+			Namespace:   storage.namespace,
+			Labels:      storage.labels,
+			Annotations: storage.annotations,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			secretDataKey: data,
+		},
+	}
+
+	_, err = kubeClient.CoreV1().Secrets(storage.namespace).Create(ctx, secret, metav1.CreateOptions{})
+	return err
+}
+
+// getInitSecret reads back the init response persisted by createInitSecret.
+// It returns ok=false (with no error) if the Secret no longer exists.
+func getInitSecret(ctx context.Context, kubeClient *kubernetes.Clientset, storage kubeSecretStorage) (*api.InitResponse, bool, error) {
+	secret, err := kubeClient.CoreV1().Secrets(storage.namespace).Get(ctx, storage.secretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var res api.InitResponse
+	if err := json.Unmarshal(secret.Data[secretDataKey], &res); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal init response from secret %s/%s: %w", storage.namespace, storage.secretName, err)
+	}
+
+	return &res, true, nil
+}
+
+// deleteInitSecret deletes the Secret created by createInitSecret.
+func deleteInitSecret(ctx context.Context, kubeClient *kubernetes.Clientset, storage kubeSecretStorage) error {
+	err := kubeClient.CoreV1().Secrets(storage.namespace).Delete(ctx, storage.secretName, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// initResponseHash returns a non-sensitive fingerprint of an InitResponse,
+// suitable for storing in Terraform state as a drift-detection marker when
+// the real keys and root token live only in the Kubernetes Secret.
+func initResponseHash(res *api.InitResponse) string {
+	data, _ := json.Marshal(res)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
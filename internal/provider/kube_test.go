@@ -0,0 +1,80 @@
+package provider
+
+import "testing"
+
+// baseKubeConfigMap returns a kube_config block map with every field the
+// code type-asserts against present and zeroed, so tests only need to
+// override the fields they care about.
+func baseKubeConfigMap() map[string]interface{} {
+	return map[string]interface{}{
+		argKubeInCluster:             false,
+		argKubeHost:                  "",
+		argKubeUsername:              "",
+		argKubePassword:              "",
+		argKubeToken:                 "",
+		argKubeInsecure:              false,
+		argKubeClusterCACert:         "",
+		argKubeClientCert:            "",
+		argKubeClientKey:             "",
+		argKubeConfigPath:            "",
+		argKubeConfigContext:         "",
+		argKubeConfigContextAuthInfo: "",
+		argKubeConfigContextCluster:  "",
+	}
+}
+
+func TestHasInlineKubeCreds(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		want bool
+	}{
+		{name: "no host set", host: "", want: false},
+		{name: "host set", host: "https://k8s.example.com", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kc := baseKubeConfigMap()
+			kc[argKubeHost] = tt.host
+
+			if got := hasInlineKubeCreds(kc); got != tt.want {
+				t.Errorf("hasInlineKubeCreds(host=%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildRestConfigInline(t *testing.T) {
+	kc := baseKubeConfigMap()
+	kc[argKubeHost] = "https://k8s.example.com"
+	kc[argKubeToken] = "test-token"
+	kc[argKubeInsecure] = true
+
+	restConfig, err := buildRestConfig(kc)
+	if err != nil {
+		t.Fatalf("buildRestConfig returned error: %v", err)
+	}
+
+	if restConfig.Host != "https://k8s.example.com" {
+		t.Errorf("restConfig.Host = %q, want %q", restConfig.Host, "https://k8s.example.com")
+	}
+	if restConfig.BearerToken != "test-token" {
+		t.Errorf("restConfig.BearerToken = %q, want %q", restConfig.BearerToken, "test-token")
+	}
+	if !restConfig.Insecure {
+		t.Errorf("restConfig.Insecure = false, want true")
+	}
+}
+
+func TestBuildRestConfigInClusterOutsidePod(t *testing.T) {
+	kc := baseKubeConfigMap()
+	kc[argKubeInCluster] = true
+
+	// rest.InClusterConfig() requires the service account files the
+	// Kubernetes control plane mounts into a Pod; running this test outside
+	// a cluster should surface that as an error rather than panic.
+	if _, err := buildRestConfig(kc); err == nil {
+		t.Errorf("buildRestConfig(in_cluster=true) returned no error outside a Pod")
+	}
+}
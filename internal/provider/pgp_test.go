@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/packet"
+)
+
+func TestResolveKeybaseKeysPassthrough(t *testing.T) {
+	in := []string{"not-a-keybase-ref", "YmFzZTY0"}
+
+	resolved, changed, err := resolveKeybaseKeys(in)
+	if err != nil {
+		t.Fatalf("resolveKeybaseKeys returned error: %v", err)
+	}
+	if changed {
+		t.Fatalf("resolveKeybaseKeys reported changed=true for keys with no keybase: prefix")
+	}
+	if !reflect.DeepEqual(resolved, in) {
+		t.Fatalf("resolveKeybaseKeys(%v) = %v, want unchanged", in, resolved)
+	}
+}
+
+func TestResolveKeybaseKeysResolvesPrefixedEntries(t *testing.T) {
+	armoredKey, fingerprint := newTestArmoredKey(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(armoredKey))
+	}))
+	defer server.Close()
+
+	origURL := keybasePubKeyURL
+	keybasePubKeyURL = server.URL + "/%s/pgp_keys.asc"
+	defer func() { keybasePubKeyURL = origURL }()
+
+	resolved, changed, err := resolveKeybaseKeys([]string{"keybase:testuser", "already-base64"})
+	if err != nil {
+		t.Fatalf("resolveKeybaseKeys returned error: %v", err)
+	}
+	if !changed {
+		t.Fatalf("resolveKeybaseKeys reported changed=false, want true")
+	}
+	if resolved[1] != "already-base64" {
+		t.Fatalf("resolveKeybaseKeys altered a non-keybase entry: got %q", resolved[1])
+	}
+
+	assertResolvedKeyMatches(t, resolved[0], fingerprint)
+}
+
+func TestFetchKeybasePubKeyDearmors(t *testing.T) {
+	armoredKey, fingerprint := newTestArmoredKey(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(armoredKey))
+	}))
+	defer server.Close()
+
+	origURL := keybasePubKeyURL
+	keybasePubKeyURL = server.URL + "/%s/pgp_keys.asc"
+	defer func() { keybasePubKeyURL = origURL }()
+
+	resolved, err := fetchKeybasePubKey("testuser")
+	if err != nil {
+		t.Fatalf("fetchKeybasePubKey returned error: %v", err)
+	}
+
+	assertResolvedKeyMatches(t, resolved, fingerprint)
+}
+
+func TestFetchKeybasePubKeyErrorsOnNon200(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	origURL := keybasePubKeyURL
+	keybasePubKeyURL = server.URL + "/%s/pgp_keys.asc"
+	defer func() { keybasePubKeyURL = origURL }()
+
+	if _, err := fetchKeybasePubKey("testuser"); err == nil {
+		t.Fatalf("fetchKeybasePubKey returned no error for a 404 response")
+	}
+}
+
+// newTestArmoredKey generates a throwaway RSA key pair and returns its
+// ASCII-armored public key (the form keybase.io serves) along with its
+// key ID, for comparing against the dearmored result.
+func newTestArmoredKey(t *testing.T) (string, string) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("test", "", "test@example.com", &packet.Config{RSABits: 1024})
+	if err != nil {
+		t.Fatalf("failed to generate test PGP key: %v", err)
+	}
+
+	var buf bytes.Buffer
+	armorWriter, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("failed to open armor writer: %v", err)
+	}
+	if err := entity.Serialize(armorWriter); err != nil {
+		t.Fatalf("failed to serialize test PGP key: %v", err)
+	}
+	if err := armorWriter.Close(); err != nil {
+		t.Fatalf("failed to close armor writer: %v", err)
+	}
+
+	return buf.String(), entity.PrimaryKey.KeyIdString()
+}
+
+// assertResolvedKeyMatches decodes a resolved pgp_keys entry the way Vault
+// does (base64 of a raw packet stream, no armor) and checks it parses back
+// to the expected key.
+func assertResolvedKeyMatches(t *testing.T, resolved, wantFingerprint string) {
+	t.Helper()
+
+	raw, err := base64.StdEncoding.DecodeString(resolved)
+	if err != nil {
+		t.Fatalf("resolved key is not valid base64: %v", err)
+	}
+
+	entityList, err := openpgp.ReadKeyRing(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("resolved key did not parse as a raw OpenPGP packet stream (still armored?): %v", err)
+	}
+	if len(entityList) != 1 {
+		t.Fatalf("expected exactly one entity, got %d", len(entityList))
+	}
+	if got := entityList[0].PrimaryKey.KeyIdString(); got != wantFingerprint {
+		t.Fatalf("resolved key ID = %s, want %s", got, wantFingerprint)
+	}
+}
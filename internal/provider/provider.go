@@ -6,12 +6,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/vault/api"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 	"log"
 	"os"
-	"strings"
 )
 
 const (
@@ -27,6 +23,32 @@ const (
 	argServiceName    = "service"
 	argLocalPort      = "local_port"
 	argRemotePort     = "remote_port"
+
+	argKubeHost                  = "host"
+	argKubeUsername              = "username"
+	argKubePassword              = "password"
+	argKubeInsecure              = "insecure"
+	argKubeClusterCACert         = "cluster_ca_certificate"
+	argKubeClientCert            = "client_certificate"
+	argKubeClientKey             = "client_key"
+	argKubeToken                 = "token"
+	argKubeConfigContext         = "config_context"
+	argKubeConfigContextAuthInfo = "config_context_auth_info"
+	argKubeConfigContextCluster  = "config_context_cluster"
+	argKubeInCluster             = "in_cluster"
+
+	envKubeHost              = "KUBE_HOST"
+	envKubeUser              = "KUBE_USER"
+	envKubePassword          = "KUBE_PASSWORD"
+	envKubeInsecure          = "KUBE_INSECURE"
+	envKubeClusterCACertData = "KUBE_CLUSTER_CA_CERT_DATA"
+	envKubeClientCertData    = "KUBE_CLIENT_CERT_DATA"
+	envKubeClientKeyData     = "KUBE_CLIENT_KEY_DATA"
+	envKubeToken             = "KUBE_TOKEN"
+	envKubeCtx               = "KUBE_CTX"
+	envKubeCtxAuthInfo       = "KUBE_CTX_AUTH_INFO"
+	envKubeCtxCluster        = "KUBE_CTX_CLUSTER"
+	envKubeInCluster         = "KUBE_IN_CLUSTER"
 )
 
 func init() {
@@ -50,10 +72,12 @@ func New(version string) func() *schema.Provider {
 		p := &schema.Provider{
 			Schema: providerSchema(),
 			ResourcesMap: map[string]*schema.Resource{
-				resInit: resourceInit(),
+				resInit:   resourceInit(),
+				resUnseal: resourceUnseal(),
 			},
 			DataSourcesMap: map[string]*schema.Resource{
-				resInit: providerDatasource(),
+				resInit:       providerDatasource(),
+				resInitStatus: providerDatasourceInitStatus(),
 			},
 		}
 
@@ -63,16 +87,6 @@ func New(version string) func() *schema.Provider {
 	}
 }
 
-type kubeConn struct {
-	configPath  string
-	nameSpace   string
-	serviceName string
-	localPort   string
-	remotePort  string
-	kubeConfig  *rest.Config
-	kubeClient  *kubernetes.Clientset
-}
-
 type apiClient struct {
 	// Add whatever fields, client or connection info, etc. here
 	// you would need to setup to communicate with the upstream
@@ -110,7 +124,7 @@ func providerSchema() map[string]*schema.Schema {
 					argKubeConfigPath: {
 						Type:        schema.TypeString,
 						Optional:    true,
-						Description: "Full path to a Kubernetes config",
+						Description: "Full path to a Kubernetes config. Ignored when `host` is set or `in_cluster` is true.",
 						Default:     "~/.kube/config",
 					},
 					argNameSpace: {
@@ -135,6 +149,78 @@ func providerSchema() map[string]*schema.Schema {
 						Description: "Remote service port to forward",
 						Default:     "8200",
 					},
+					argKubeHost: {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "The hostname (in form of URI) of the Kubernetes API server. Takes precedence over `path` when set.",
+						DefaultFunc: schema.EnvDefaultFunc(envKubeHost, ""),
+					},
+					argKubeUsername: {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "The username to use for HTTP basic authentication when accessing the Kubernetes API server",
+						DefaultFunc: schema.EnvDefaultFunc(envKubeUser, ""),
+					},
+					argKubePassword: {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Sensitive:   true,
+						Description: "The password to use for HTTP basic authentication when accessing the Kubernetes API server",
+						DefaultFunc: schema.EnvDefaultFunc(envKubePassword, ""),
+					},
+					argKubeInsecure: {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Description: "Whether server should be accessed without verifying the TLS certificate",
+						DefaultFunc: schema.EnvDefaultFunc(envKubeInsecure, false),
+					},
+					argKubeClusterCACert: {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "PEM-encoded root certificates bundle for TLS authentication",
+						DefaultFunc: schema.EnvDefaultFunc(envKubeClusterCACertData, ""),
+					},
+					argKubeClientCert: {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "PEM-encoded client certificate for TLS authentication",
+						DefaultFunc: schema.EnvDefaultFunc(envKubeClientCertData, ""),
+					},
+					argKubeClientKey: {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Sensitive:   true,
+						Description: "PEM-encoded client certificate key for TLS authentication",
+						DefaultFunc: schema.EnvDefaultFunc(envKubeClientKeyData, ""),
+					},
+					argKubeToken: {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Sensitive:   true,
+						Description: "Token of your service account",
+						DefaultFunc: schema.EnvDefaultFunc(envKubeToken, ""),
+					},
+					argKubeConfigContext: {
+						Type:        schema.TypeString,
+						Optional:    true,
+						DefaultFunc: schema.EnvDefaultFunc(envKubeCtx, ""),
+					},
+					argKubeConfigContextAuthInfo: {
+						Type:        schema.TypeString,
+						Optional:    true,
+						DefaultFunc: schema.EnvDefaultFunc(envKubeCtxAuthInfo, ""),
+					},
+					argKubeConfigContextCluster: {
+						Type:        schema.TypeString,
+						Optional:    true,
+						DefaultFunc: schema.EnvDefaultFunc(envKubeCtxCluster, ""),
+					},
+					argKubeInCluster: {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Description: "Use the Pod's mounted service account (in-cluster config) instead of a kubeconfig file or inline credentials. Only valid when Terraform itself is running inside the cluster.",
+						DefaultFunc: schema.EnvDefaultFunc(envKubeInCluster, false),
+					},
 				},
 			},
 		},
@@ -148,29 +234,11 @@ func configure(version string, p *schema.Provider) func(context.Context, *schema
 		if k := d.Get(argKubeConfig).([]interface{}); len(k) > 0 {
 			kubeConn := k[0].(map[string]interface{})
 
-			path := kubeConn[argKubeConfigPath].(string)
-
-			if strings.Contains(path, "~") {
-				homeDir, err := homeDir()
-				if err != nil {
-					return nil, diag.FromErr(err)
-				}
-				path = strings.Replace(path, "~", homeDir, -1)
-			}
-
-			// Create Kubernetes *rest.Config
-			kubeConfig, err := clientcmd.BuildConfigFromFlags("", path)
-			if err != nil {
-				return nil, diag.FromErr(err)
-			}
-			a.kubeConn.kubeConfig = kubeConfig
-
-			// Create Kubernetes *kubernetes.Clientset
-			kubeClient, err := kubernetes.NewForConfig(a.kubeConn.kubeConfig)
+			conn, err := buildKubeConn(kubeConn)
 			if err != nil {
 				return nil, diag.FromErr(err)
 			}
-			a.kubeConn.kubeClient = kubeClient
+			a.kubeConn = *conn
 
 			if namespace := kubeConn[argNameSpace].(string); namespace != "" {
 				a.kubeConn.nameSpace = namespace
@@ -187,6 +255,27 @@ func configure(version string, p *schema.Provider) func(context.Context, *schema
 			a.kubeConn.localPort = kubeConn[argLocalPort].(string)
 			a.kubeConn.remotePort = kubeConn[argRemotePort].(string)
 
+			if err := startPortForward(ctx, &a.kubeConn); err != nil {
+				return nil, diag.FromErr(err)
+			}
+
+			// ctx here is scoped to the single ConfigureProvider RPC and is
+			// cancelled as soon as this function returns, so it can't be
+			// used to detect provider shutdown. schema.StopContext gives
+			// us the provider-lifetime context Terraform cancels on a real
+			// stop; fall back to a context that never cancels if it's
+			// unavailable (e.g. in tests).
+			stopContext, ok := schema.StopContext(ctx)
+			if !ok {
+				stopContext = context.Background()
+			}
+
+			stopCh := a.kubeConn.stopCh
+			go func() {
+				<-stopContext.Done()
+				close(stopCh)
+			}()
+
 			a.url = fmt.Sprintf("http://localhost:%s", a.kubeConn.localPort)
 		} else {
 			if u := d.Get(argVaultAddr).(string); u != "" {
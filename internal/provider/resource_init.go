@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/url"
 	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -19,6 +21,22 @@ const (
 	argRootToken       = "root_token"
 	argKeys            = "keys"
 	argKeysBase64      = "keys_base64"
+	argInitHash        = "init_hash"
+
+	argPGPKeys            = "pgp_keys"
+	argRootTokenPGPKey    = "root_token_pgp_key"
+	argRecoveryShares     = "recovery_shares"
+	argRecoveryThreshold  = "recovery_threshold"
+	argRecoveryPGPKeys    = "recovery_pgp_keys"
+	argRecoveryKeys       = "recovery_keys"
+	argRecoveryKeysBase64 = "recovery_keys_base64"
+
+	argStorage            = "storage"
+	argStorageKubernetes  = "kubernetes"
+	argStorageSecretName  = "secret_name"
+	argStorageNamespace   = "namespace"
+	argStorageLabels      = "labels"
+	argStorageAnnotations = "annotations"
 )
 
 func resourceInit() *schema.Resource {
@@ -69,17 +87,153 @@ func resourceInit() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			argInitHash: {
+				Description: "SHA-256 fingerprint of the init response. Only populated when `storage.kubernetes` is set, as a drift-detection marker in place of the real keys.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			argPGPKeys: {
+				Description: "List of PGP public keys used to encrypt the output unseal keys, one per key share. Each entry may be a base64-encoded public key or a `keybase:<username>` reference, which is resolved via Keybase when the resource is created.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			argRootTokenPGPKey: {
+				Description: "PGP public key used to encrypt the initial root token. May be a base64-encoded public key or a `keybase:<username>` reference.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			argRecoveryShares: {
+				Description: "Specifies the number of shares to split the recovery key into. Only used when Vault is configured with an auto-unseal mechanism.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			argRecoveryThreshold: {
+				Description: "Specifies the number of shares required to reconstruct the recovery key.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			argRecoveryPGPKeys: {
+				Description: "List of PGP public keys used to encrypt the output recovery keys, one per key share. Same format as `pgp_keys`.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			argRecoveryKeys: {
+				Description: "The recovery keys, returned when `recovery_shares` is set.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			argRecoveryKeysBase64: {
+				Description: "The recovery keys, base64 encoded.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			argStorage: {
+				Description: "Where to persist the result of `vault operator init` instead of Terraform state. When set, Terraform state holds only `init_hash`, not the keys or root token.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						argStorageKubernetes: {
+							Description: "Persist the init response as a Kubernetes Secret so it never has to live in `terraform.tfstate`.",
+							Type:        schema.TypeList,
+							Optional:    true,
+							MaxItems:    1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									argStorageSecretName: {
+										Description: "Name of the Secret to create.",
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+									argStorageNamespace: {
+										Description: "Namespace to create the Secret in.",
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+									argStorageLabels: {
+										Description: "Labels to set on the Secret.",
+										Type:        schema.TypeMap,
+										Optional:    true,
+										Elem:        &schema.Schema{Type: schema.TypeString},
+									},
+									argStorageAnnotations: {
+										Description: "Annotations to set on the Secret.",
+										Type:        schema.TypeMap,
+										Optional:    true,
+										Elem:        &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
+func toStringSlice(in []interface{}) []string {
+	out := make([]string, len(in))
+	for i, v := range in {
+		out[i] = v.(string)
+	}
+	return out
+}
+
 func resourceInitCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	// use the meta value to retrieve your client from the provider configure method
 	client := meta.(*apiClient)
 
+	pgpKeys, _, err := resolveKeybaseKeys(toStringSlice(d.Get(argPGPKeys).([]interface{})))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	recoveryPGPKeys, _, err := resolveKeybaseKeys(toStringSlice(d.Get(argRecoveryPGPKeys).([]interface{})))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	rootTokenPGPKey, err := resolveKeybaseKey(d.Get(argRootTokenPGPKey).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Check that storage.kubernetes has a Kubernetes client to persist with
+	// before making the irreversible Sys().Init() call below: once Vault is
+	// initialized, the unseal keys and root token only ever exist in that
+	// one InitResponse, so failing after Init with nowhere to put it would
+	// lose them permanently. This does not check that the Secret itself can
+	// be created (for example that its name is not already taken).
+	storage, storageOK := kubeStorageFromResourceData(d)
+	if storageOK && client.kubeConn.kubeClient == nil {
+		return diag.Errorf("%s.%s requires a kube_config block on the provider", argStorage, argStorageKubernetes)
+	}
+
 	req := api.InitRequest{
-		SecretShares:    d.Get(argSecretShares).(int),
-		SecretThreshold: d.Get(argSecretThreshold).(int),
+		SecretShares:      d.Get(argSecretShares).(int),
+		SecretThreshold:   d.Get(argSecretThreshold).(int),
+		PGPKeys:           pgpKeys,
+		RootTokenPGPKey:   rootTokenPGPKey,
+		RecoveryShares:    d.Get(argRecoveryShares).(int),
+		RecoveryThreshold: d.Get(argRecoveryThreshold).(int),
+		RecoveryPGPKeys:   recoveryPGPKeys,
 	}
 
 	res, err := client.client.Sys().Init(&req)
@@ -89,16 +243,64 @@ func resourceInitCreate(ctx context.Context, d *schema.ResourceData, meta interf
 		return diag.FromErr(err)
 	}
 
+	if storageOK {
+		if err := createInitSecret(ctx, client.kubeConn.kubeClient, storage, res); err != nil {
+			logError("failed to persist init response to Kubernetes secret %s/%s: %v", storage.namespace, storage.secretName, err)
+			return diag.FromErr(err)
+		}
+
+		d.SetId(storage.id())
+		d.Set(argInitHash, initResponseHash(res))
+
+		return diag.Diagnostics{}
+	}
+
 	updateState(d, client.client.Address(), res)
 
 	return diag.Diagnostics{}
 }
 
 func resourceInitRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	// use the meta value to retrieve your client from the provider configure method
-	// client := meta.(*apiClient)
+	client := meta.(*apiClient)
 
-	return diag.Errorf("not implemented")
+	if storage, ok := kubeStorageFromResourceData(d); ok {
+		if client.kubeConn.kubeClient == nil {
+			return diag.Errorf("%s.%s requires a kube_config block on the provider", argStorage, argStorageKubernetes)
+		}
+
+		res, found, err := getInitSecret(ctx, client.kubeConn.kubeClient, storage)
+		if err != nil {
+			logError("failed to read init response from Kubernetes secret %s/%s: %v", storage.namespace, storage.secretName, err)
+			return diag.FromErr(err)
+		}
+		if !found {
+			d.SetId("")
+			return nil
+		}
+
+		d.Set(argInitHash, initResponseHash(res))
+
+		return nil
+	}
+
+	initialized, err := client.client.Sys().InitStatus()
+	if err != nil {
+		logError("failed to check init status: %v", err)
+		return diag.FromErr(err)
+	}
+
+	if !initialized {
+		// The PVC backing Vault's storage was likely wiped out from under
+		// us; nothing we saved (root token, keys) is still valid.
+		d.SetId("")
+		return nil
+	}
+
+	// keys/root_token can't be re-read from Vault once it's initialized, so
+	// leave them as-is; only the address (and hence the ID) can drift.
+	d.SetId(client.client.Address())
+
+	return nil
 }
 
 func resourceInitUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -109,16 +311,30 @@ func resourceInitUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 }
 
 func resourceInitDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	// use the meta value to retrieve your client from the provider configure method
-	// client := meta.(*apiClient)
+	client := meta.(*apiClient)
+
+	if storage, ok := kubeStorageFromResourceData(d); ok {
+		if client.kubeConn.kubeClient == nil {
+			return diag.Errorf("%s.%s requires a kube_config block on the provider", argStorage, argStorageKubernetes)
+		}
+
+		if err := deleteInitSecret(ctx, client.kubeConn.kubeClient, storage); err != nil {
+			logError("failed to delete Kubernetes secret %s/%s: %v", storage.namespace, storage.secretName, err)
+			return diag.FromErr(err)
+		}
+
+		return nil
+	}
 
 	return diag.Errorf("not implemented")
 }
 
 func resourceInitImporter(c context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client := meta.(*apiClient)
-	// Id should be a file scheme URL: file://path_to_file.json
-	// The json file schema should be the same as what's returned from the sys/init API (i.e. a InitResponse)
+	// Id should be either a file scheme URL (file://path_to_file.json, whose
+	// contents are the same JSON schema returned from the sys/init API, i.e.
+	// an InitResponse) or a k8s scheme URL (k8s://namespace/secret_name)
+	// pointing at a Secret created by the storage.kubernetes block.
 	id := d.Id()
 
 	u, err := url.Parse(id)
@@ -127,25 +343,44 @@ func resourceInitImporter(c context.Context, d *schema.ResourceData, meta interf
 		return nil, err
 	}
 
-	if u.Scheme != "file" {
-		logError("unsupported scheme")
-		return nil, errors.New("unsupported scheme")
-	}
+	switch u.Scheme {
+	case "file":
+		fc, err := ioutil.ReadFile(filepath.Join(u.Host, u.Path))
+		if err != nil {
+			logError("failed reading file %v", err)
+			return nil, err
+		}
 
-	fc, err := ioutil.ReadFile(filepath.Join(u.Host, u.Path))
+		var initResponse api.InitResponse
+		if err := json.Unmarshal(fc, &initResponse); err != nil {
+			logError("failed unmarshalling json: %v", err)
+			return nil, err
+		}
 
-	if err != nil {
-		logError("failed reading file %v", err)
-		return nil, err
-	}
+		updateState(d, client.client.Address(), &initResponse)
+	case "k8s":
+		if client.kubeConn.kubeClient == nil {
+			return nil, errors.New("importing a k8s:// id requires a kube_config block on the provider")
+		}
 
-	var initResponse api.InitResponse
-	if err := json.Unmarshal(fc, &initResponse); err != nil {
-		logError("failed unmarshalling json: %v", err)
-		return nil, err
+		storage := kubeSecretStorage{namespace: u.Host, secretName: strings.TrimPrefix(u.Path, "/")}
+
+		res, found, err := getInitSecret(c, client.kubeConn.kubeClient, storage)
+		if err != nil {
+			logError("failed reading secret %v", err)
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf("secret %s/%s not found", storage.namespace, storage.secretName)
+		}
+
+		d.SetId(storage.id())
+		d.Set(argInitHash, initResponseHash(res))
+	default:
+		logError("unsupported scheme")
+		return nil, errors.New("unsupported scheme")
 	}
 
-	updateState(d, client.client.Address(), &initResponse)
 	return []*schema.ResourceData{d}, nil
 }
 
@@ -154,4 +389,6 @@ func updateState(d *schema.ResourceData, id string, res *api.InitResponse) {
 	d.Set(argRootToken, res.RootToken)
 	d.Set(argKeys, res.Keys)
 	d.Set(argKeysBase64, res.KeysB64)
-}
\ No newline at end of file
+	d.Set(argRecoveryKeys, res.RecoveryKeys)
+	d.Set(argRecoveryKeysBase64, res.RecoveryKeysB64)
+}
@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/vault/api"
+)
+
+const (
+	resUnseal = provider + "_unseal"
+
+	argUnsealKeys    = "keys"
+	argThreshold     = "threshold"
+	argReset         = "reset"
+	argSealOnDestroy = "seal_on_destroy"
+	argSealed        = "sealed"
+	argProgress      = "progress"
+	argT             = "t"
+	argN             = "n"
+	argVersion       = "version"
+	argClusterName   = "cluster_name"
+	argClusterId     = "cluster_id"
+)
+
+func resourceUnseal() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource for unsealing Vault with the keys produced by vaultoperator_init.",
+
+		CreateContext: resourceUnsealCreate,
+		ReadContext:   resourceUnsealRead,
+		UpdateContext: resourceUnsealUpdate,
+		DeleteContext: resourceUnsealDelete,
+
+		Schema: map[string]*schema.Schema{
+			argUnsealKeys: {
+				Description: "The unseal keys to submit.",
+				Type:        schema.TypeList,
+				Required:    true,
+				Sensitive:   true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			argThreshold: {
+				Description: "Number of keys required to unseal Vault. Defaults to the number of keys given.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+			},
+			argReset: {
+				Description: "Reset any in-progress unseal process before submitting keys.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			argSealOnDestroy: {
+				Description: "Re-seal Vault when this resource is destroyed.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			argSealed: {
+				Description: "Whether Vault is currently sealed.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			argProgress: {
+				Description: "Number of unseal keys that have been submitted so far in the current unseal process.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			argT: {
+				Description: "Threshold required to unseal, as reported by Vault.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			argN: {
+				Description: "Number of key shares Vault was initialized with, as reported by Vault.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			argVersion: {
+				Description: "Vault server version.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			argClusterName: {
+				Description: "Vault cluster name.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			argClusterId: {
+				Description: "Vault cluster ID.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceUnsealCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient)
+
+	keys := toStringSlice(d.Get(argUnsealKeys).([]interface{}))
+
+	threshold := d.Get(argThreshold).(int)
+	if threshold == 0 {
+		threshold = len(keys)
+	}
+
+	if d.Get(argReset).(bool) {
+		if _, err := client.client.Sys().ResetUnsealProcess(); err != nil {
+			logError("failed to reset unseal process: %v", err)
+			return diag.FromErr(err)
+		}
+	}
+
+	var status *api.SealStatusResponse
+	for i := 0; i < threshold && i < len(keys); i++ {
+		res, err := client.client.Sys().Unseal(keys[i])
+		if err != nil {
+			logError("failed to submit unseal key: %v", err)
+			return diag.FromErr(err)
+		}
+
+		status = res
+		if !status.Sealed {
+			break
+		}
+	}
+
+	d.SetId(client.client.Address())
+	d.Set(argThreshold, threshold)
+	updateSealStatus(d, status)
+
+	return diag.Diagnostics{}
+}
+
+func resourceUnsealRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient)
+
+	status, err := client.client.Sys().SealStatus()
+	if err != nil {
+		logError("failed to read seal status: %v", err)
+		return diag.FromErr(err)
+	}
+
+	updateSealStatus(d, status)
+
+	return diag.Diagnostics{}
+}
+
+func resourceUnsealUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceUnsealCreate(ctx, d, meta)
+}
+
+func resourceUnsealDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient)
+
+	if d.Get(argSealOnDestroy).(bool) {
+		if err := client.client.Sys().Seal(); err != nil {
+			logError("failed to seal Vault: %v", err)
+			return diag.FromErr(err)
+		}
+	}
+
+	return diag.Diagnostics{}
+}
+
+func updateSealStatus(d *schema.ResourceData, status *api.SealStatusResponse) {
+	if status == nil {
+		return
+	}
+
+	d.Set(argSealed, status.Sealed)
+	d.Set(argProgress, status.Progress)
+	d.Set(argT, status.T)
+	d.Set(argN, status.N)
+	d.Set(argVersion, status.Version)
+	d.Set(argClusterName, status.ClusterName)
+	d.Set(argClusterId, status.ClusterID)
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+)
+
+func TestUpdateSealStatus(t *testing.T) {
+	d := resourceUnseal().TestResourceData()
+
+	updateSealStatus(d, &api.SealStatusResponse{
+		Sealed:      true,
+		T:           3,
+		N:           5,
+		Progress:    1,
+		Version:     "1.15.0",
+		ClusterName: "vault-cluster",
+		ClusterID:   "cluster-id",
+	})
+
+	if got := d.Get(argSealed).(bool); !got {
+		t.Errorf("%s = %v, want true", argSealed, got)
+	}
+	if got := d.Get(argT).(int); got != 3 {
+		t.Errorf("%s = %d, want 3", argT, got)
+	}
+	if got := d.Get(argN).(int); got != 5 {
+		t.Errorf("%s = %d, want 5", argN, got)
+	}
+	if got := d.Get(argProgress).(int); got != 1 {
+		t.Errorf("%s = %d, want 1", argProgress, got)
+	}
+	if got := d.Get(argVersion).(string); got != "1.15.0" {
+		t.Errorf("%s = %q, want %q", argVersion, got, "1.15.0")
+	}
+	if got := d.Get(argClusterName).(string); got != "vault-cluster" {
+		t.Errorf("%s = %q, want %q", argClusterName, got, "vault-cluster")
+	}
+	if got := d.Get(argClusterId).(string); got != "cluster-id" {
+		t.Errorf("%s = %q, want %q", argClusterId, got, "cluster-id")
+	}
+}
+
+func TestUpdateSealStatusNilIsNoOp(t *testing.T) {
+	d := resourceUnseal().TestResourceData()
+
+	// Should not panic, and should leave fields at their zero values.
+	updateSealStatus(d, nil)
+
+	if got := d.Get(argSealed).(bool); got {
+		t.Errorf("%s = %v, want false", argSealed, got)
+	}
+}